@@ -2,47 +2,67 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
-	"sync"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/hub"
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/metrics"
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/responder"
 	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/store/pgstore"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type apiHandler struct {
-	q           *pgstore.Queries
-	r           *chi.Mux
-	upgrader    websocket.Upgrader
-	subscribers map[string]map[*websocket.Conn]context.CancelFunc
-	mu          *sync.Mutex
+	q         *pgstore.Queries
+	pool      *pgxpool.Pool
+	r         *chi.Mux
+	upgrader  websocket.Upgrader
+	hub       *hub.Hub
+	jwtSecret []byte
 }
 
 func (h apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.r.ServeHTTP(w, r)
 }
 
-func NewHandler(q *pgstore.Queries) http.Handler {
+// NewHandler wires up the API. broker backs the websocket hub's
+// cross-instance fan-out; pass nil for a single-instance deployment, which
+// defaults to hub.NewInMemoryBroker(), or hub.NewRedisBroker(...) to share
+// subscribers for a room across multiple API instances.
+func NewHandler(pool *pgxpool.Pool, q *pgstore.Queries, jwtSecret []byte, broker hub.Broker) http.Handler {
+	if broker == nil {
+		broker = hub.NewInMemoryBroker()
+	}
+
 	a := apiHandler{
-		q:           q,
-		upgrader:    websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
-		subscribers: make(map[string]map[*websocket.Conn]context.CancelFunc),
-		mu:          &sync.Mutex{},
+		q:         q,
+		pool:      pool,
+		upgrader:  websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
+		hub:       hub.New(broker),
+		jwtSecret: jwtSecret,
 	}
 
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID, middleware.Recoverer, middleware.Logger)
+	r.Use(a.tracingMiddleware, a.metricsMiddleware)
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
@@ -53,15 +73,20 @@ func NewHandler(q *pgstore.Queries) http.Handler {
 		MaxAge:           300,
 	}))
 
-	r.Get("/subscribe/{room_id}", a.handleSubscribe)
+	r.Handle("/metrics", promhttp.Handler())
+
+	r.With(a.authMiddleware).Get("/subscribe/{room_id}", a.handleSubscribe)
 
 	r.Route("/api", func(r chi.Router) {
+		r.Use(a.authMiddleware)
+
 		r.Route("/rooms", func(r chi.Router) {
 			r.Post("/", a.handleCreateRoom)
 			r.Get("/", a.handleGetRooms)
 
 			r.Route("/{room_id}/messages", func(r chi.Router) {
 				r.Post("/", a.handleCreateRoomMessage)
+				r.Post("/bulk", a.handleCreateRoomMessagesBulk)
 				r.Get("/", a.handleGetRoomMessages)
 
 				r.Route("/{message_id}", func(r chi.Router) {
@@ -80,121 +105,280 @@ func NewHandler(q *pgstore.Queries) http.Handler {
 
 const (
 	MessageKindMessageCreated          = "message_created"
+	MessageKindMessageCreatedBulk      = "message_created_bulk"
 	MessageKindReactedToMessage        = "reacted_to_message"
 	MessageKindRemovedReactFromMessage = "removed_reaction_from_message"
 	MessageKindMarkMessageAsAnswered   = "marked_message_as_answered"
+
+	// Ephemeral, client-originated events: never persisted, and fanned out
+	// to local subscribers only (see hub.BroadcastLocalExcept).
+	MessageKindTyping      = "typing"
+	MessageKindPresence    = "presence"
+	MessageKindMessageSeen = "message_seen"
+)
+
+// Client-sent websocket command kinds, parsed by handleSubscribe's reader
+// callback.
+const (
+	clientCommandTypingStart  = "typing_start"
+	clientCommandPresencePing = "presence_ping"
+	clientCommandAck          = "ack"
+)
+
+const (
+	// defaultMessagesPageSize and maxMessagesPageSize bound the ?limit=
+	// query parameter accepted by handleGetRoomMessages.
+	defaultMessagesPageSize = 20
+	maxMessagesPageSize     = 100
+
+	// maxBulkMessages bounds how many messages a single bulk request may
+	// insert, so one request can't monopolize a transaction.
+	maxBulkMessages = 50
 )
 
 type MessageReactedToMessage struct {
-	ID    string `json:"id"`
-	Value int64  `json:"value"`
+	ID       string `json:"id"`
+	Value    int64  `json:"value"`
+	AuthorID string `json:"author_id"`
 }
 type MessageRemovedReactFromMessage struct {
 	ID    string `json:"id"`
 	Value int64  `json:"value"`
 }
 type MessageMessageCreated struct {
-	ID      string `json:"id"`
-	Message string `json:"message"`
+	ID       string `json:"id"`
+	Message  string `json:"message"`
+	AuthorID string `json:"author_id,omitempty"`
+}
+
+type MessageMessageCreatedBulk struct {
+	Messages []MessageMessageCreated `json:"messages"`
 }
 
 type MessageMarkMessageAsAnswered struct {
 	ID string `json:"id"`
 }
 
+type MessageTyping struct {
+	AuthorID string `json:"author_id,omitempty"`
+}
+type MessagePresence struct {
+	AuthorID string `json:"author_id,omitempty"`
+}
+type MessageSeen struct {
+	MessageID string `json:"message_id"`
+	AuthorID  string `json:"author_id,omitempty"`
+}
+
 type Message struct {
 	Kind   string `json:"kind"`
 	Value  any    `json:"value"`
 	RoomID string `json:"-"`
 }
 
-func (h apiHandler) notifyClients(msg Message) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	subscribers, ok := h.subscribers[msg.RoomID]
-	if !ok || len(subscribers) == 0 {
-		return
-	}
-
-	for conn, cancel := range subscribers {
-		if err := conn.WriteJSON(msg); err != nil {
-			slog.Error("failed to send message to client", "error", err)
-			cancel()
-		}
-	}
+// notifyClients broadcasts msg to every subscriber of msg.RoomID, recording
+// the local subscriber count on its own span (child of the request span in
+// ctx) so slow-client incidents show up alongside the request that
+// triggered them. Every call site runs this in a goroutine right before its
+// handler returns, so by the time it runs the request span in ctx has
+// usually already ended; adding an event to that span would silently be
+// dropped by the SDK. Starting a fresh span here keeps it open for the
+// duration of this call regardless of the parent's lifetime, while staying
+// attached to the same trace. Per-connection write errors are logged by the
+// hub itself as they happen, since by the time they occur this span has
+// usually already ended too.
+func (h apiHandler) notifyClients(ctx context.Context, msg Message) {
+	_, span := tracer.Start(ctx, "notifyClients")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("room_id", msg.RoomID),
+		attribute.Int("subscriber_count", h.hub.LocalSubscriberCount(msg.RoomID)),
+	)
+
+	h.hub.Broadcast(msg.RoomID, msg)
 }
 
 func (h apiHandler) getPathID(w http.ResponseWriter, r *http.Request, v string) (string, uuid.UUID, error) {
 	rawPathID := chi.URLParam(r, v)
 	pathID, err := uuid.Parse(rawPathID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("invalid %s id", v), http.StatusBadRequest)
+		responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidUUID, fmt.Sprintf("invalid %s id", v))
 		return "", uuid.UUID{}, err
 	}
 
+	var notFoundCode string
 	switch v {
-		case "room_id":
-			_, err = h.q.GetRoom(r.Context(), pathID) 
-		case "message_id":
-			_, err = h.q.GetMessage(r.Context(), pathID)
+	case "room_id":
+		err = traceQuery(r.Context(), "GetRoom", func(ctx context.Context) error {
+			_, err := h.q.GetRoom(ctx, pathID)
+			return err
+		})
+		notFoundCode = responder.CodeRoomNotFound
+	case "message_id":
+		err = traceQuery(r.Context(), "GetMessage", func(ctx context.Context) error {
+			_, err := h.q.GetMessage(ctx, pathID)
+			return err
+		})
+		notFoundCode = responder.CodeMessageNotFound
 	}
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			http.Error(w, fmt.Sprintf("%s not found", v), http.StatusBadRequest)
+			responder.RespondError(w, http.StatusNotFound, notFoundCode, fmt.Sprintf("%s not found", v))
 			return "", uuid.UUID{}, err
 		}
 
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		slog.Error("failed to look up "+v, "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return "", uuid.UUID{}, err
 	}
 
 	return rawPathID, pathID, nil
 }
+// clientCommand is a frame sent by a subscriber over an already-open
+// websocket connection; see clientCommand* constants for the kinds handled.
+type clientCommand struct {
+	Kind      string `json:"kind"`
+	MessageID string `json:"message_id"`
+}
+
 func (h apiHandler) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		respondAuthError(w, ErrUnauthenticated)
+		return
+	}
 
-	rawRoomID, _, err := h.getPathID(w, r, "room_id")
+	rawRoomID, roomID, err := h.getPathID(w, r, "room_id")
 	if err != nil {
 		return
 	}
 
+	var sinceID uuid.UUID
+	hasSince := false
+	if rawSince := r.URL.Query().Get("since"); rawSince != "" {
+		var err error
+		sinceID, err = uuid.Parse(rawSince)
+		if err != nil {
+			responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidUUID, "invalid since id")
+			return
+		}
+		hasSince = true
+	}
+
 	c, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Warn("failed to upgrade connection", "error", err)
-		http.Error(w, "failed to upgrade to ws connection", http.StatusBadRequest)
+		responder.RespondError(w, http.StatusBadRequest, responder.CodeUpgradeFailed, "failed to upgrade to ws connection")
 		return
 	}
 
 	defer c.Close()
 
-	ctx, cancel := context.WithCancel(r.Context())
-	h.mu.Lock()
-	if _, ok := h.subscribers[rawRoomID]; !ok {
-		h.subscribers[rawRoomID] = make(map[*websocket.Conn]context.CancelFunc)
-	}
 	slog.Info("new client connected", "room_id", rawRoomID, "client_ip", r.RemoteAddr)
-	h.subscribers[rawRoomID][c] = cancel
-	h.mu.Unlock()
 
-	<-ctx.Done()
+	onRead := func(client *hub.Client, raw []byte) {
+		var cmd clientCommand
+		if err := json.Unmarshal(raw, &cmd); err != nil {
+			slog.Warn("failed to parse websocket client command", "error", err)
+			return
+		}
+
+		switch cmd.Kind {
+		case clientCommandTypingStart:
+			h.hub.BroadcastLocalExcept(rawRoomID, Message{
+				Kind:   MessageKindTyping,
+				RoomID: rawRoomID,
+				Value:  MessageTyping{AuthorID: user.ID.String()},
+			}, client)
+
+		case clientCommandPresencePing:
+			h.hub.BroadcastLocalExcept(rawRoomID, Message{
+				Kind:   MessageKindPresence,
+				RoomID: rawRoomID,
+				Value:  MessagePresence{AuthorID: user.ID.String()},
+			}, client)
+
+		case clientCommandAck:
+			h.hub.BroadcastLocalExcept(rawRoomID, Message{
+				Kind:   MessageKindMessageSeen,
+				RoomID: rawRoomID,
+				Value:  MessageSeen{MessageID: cmd.MessageID, AuthorID: user.ID.String()},
+			}, client)
+		}
+	}
+
+	client := h.hub.Register(c, rawRoomID, onRead)
+
+	// Query the since-cursor only after registering with the hub: any
+	// message published from this point on is already queued onto client's
+	// send channel, so there's no gap between "what the replay covers" and
+	// "what live broadcast delivers".
+	var replay [][]byte
+	if hasSince {
+		var missed []pgstore.Message
+		err = traceQuery(r.Context(), "GetRoomMessagesSince", func(ctx context.Context) error {
+			var err error
+			missed, err = h.q.GetRoomMessagesSince(ctx, pgstore.GetRoomMessagesSinceParams{RoomID: roomID, SinceID: sinceID})
+			return err
+		})
+		if err != nil {
+			slog.Error("failed to load messages since cursor", "error", err)
+		}
 
-	h.mu.Lock()
-	delete(h.subscribers[rawRoomID], c)
-	h.mu.Unlock()
+		for _, m := range missed {
+			payload, err := json.Marshal(Message{
+				Kind:   MessageKindMessageCreated,
+				RoomID: rawRoomID,
+				Value:  MessageMessageCreated{ID: m.ID.String(), Message: m.Message, AuthorID: m.AuthorID.String()},
+			})
+			if err != nil {
+				slog.Error("failed to marshal replayed message", "error", err)
+				continue
+			}
+			replay = append(replay, payload)
+		}
+	}
+
+	client.Serve(replay...)
 }
 func (h apiHandler) handleCreateRoom(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		respondAuthError(w, ErrUnauthenticated)
+		return
+	}
+
 	type _body struct {
 		Theme string `json:"theme"`
 	}
 	var body _body
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidBody, "invalid json")
 		return
 	}
-	roomID, err := h.q.InsertRoom(r.Context(), body.Theme)
+	var roomID uuid.UUID
+	err := traceQuery(r.Context(), "InsertRoom", func(ctx context.Context) error {
+		var err error
+		roomID, err = h.q.InsertRoom(ctx, body.Theme)
+		return err
+	})
 	if err != nil {
 		slog.Error("failed to insert room", "error", err)
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
+		return
+	}
+
+	err = traceQuery(r.Context(), "InsertRoomMember", func(ctx context.Context) error {
+		return h.q.InsertRoomMember(ctx, pgstore.InsertRoomMemberParams{
+			RoomID: roomID,
+			UserID: user.ID,
+			Role:   pgstore.RoomMemberRoleOwner,
+		})
+	})
+	if err != nil {
+		slog.Error("failed to insert room owner", "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return
 	}
 
@@ -202,37 +386,115 @@ func (h apiHandler) handleCreateRoom(w http.ResponseWriter, r *http.Request) {
 		ID string `json:"id"`
 	}
 
-	data, _ := json.Marshal(response{ID: roomID.String()})
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	responder.Respond(w, responder.Status(http.StatusOK), responder.Body(response{ID: roomID.String()}))
 }
 func (h apiHandler) handleGetRooms(w http.ResponseWriter, r *http.Request) {
-	rooms, err := h.q.GetRooms(r.Context())
+	var rooms []pgstore.Room
+	err := traceQuery(r.Context(), "GetRooms", func(ctx context.Context) error {
+		var err error
+		rooms, err = h.q.GetRooms(ctx)
+		return err
+	})
 	if err != nil {
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		slog.Error("failed to get rooms", "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return
 	}
 
-	data, _ := json.Marshal(rooms)
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	responder.Respond(w, responder.Body(rooms))
+}
+// messagesCursor identifies a position in the room-messages keyset, encoded
+// as base64("<created_at RFC3339Nano>|<id>") for the ?cursor= query param.
+type messagesCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
 
+func encodeMessagesCursor(c messagesCursor) string {
+	raw := c.CreatedAt.Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.URLEncoding.EncodeToString([]byte(raw))
 }
+
+func decodeMessagesCursor(raw string) (messagesCursor, error) {
+	decoded, err := base64.URLEncoding.DecodeString(raw)
+	if err != nil {
+		return messagesCursor{}, err
+	}
+
+	parts := strings.SplitN(string(decoded), "|", 2)
+	if len(parts) != 2 {
+		return messagesCursor{}, fmt.Errorf("malformed cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return messagesCursor{}, err
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return messagesCursor{}, err
+	}
+
+	return messagesCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
 func (h apiHandler) handleGetRoomMessages(w http.ResponseWriter, r *http.Request) {
 	_, roomID, err := h.getPathID(w, r, "room_id")
 	if err != nil {
 		return
 	}
 
-	messages, err := h.q.GetRoomMessages(r.Context(), roomID)
+	limit := defaultMessagesPageSize
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidBody, "invalid limit")
+			return
+		}
+		limit = min(parsed, maxMessagesPageSize)
+	}
+
+	var cursor messagesCursor
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		cursor, err = decodeMessagesCursor(raw)
+		if err != nil {
+			responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidBody, "invalid cursor")
+			return
+		}
+	}
+
+	// Fetch one extra row so we know whether a next page exists without a
+	// separate count query.
+	var messages []pgstore.Message
+	err = traceQuery(r.Context(), "GetRoomMessagesPage", func(ctx context.Context) error {
+		var err error
+		messages, err = h.q.GetRoomMessagesPage(ctx, pgstore.GetRoomMessagesPageParams{
+			RoomID:        roomID,
+			CursorCreated: cursor.CreatedAt,
+			CursorID:      cursor.ID,
+			PageLimit:     int32(limit + 1),
+		})
+		return err
+	})
 	if err != nil {
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		slog.Error("failed to get room messages", "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return
 	}
 
-	data, _ := json.Marshal(messages)
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	type response struct {
+		Items      []pgstore.Message `json:"items"`
+		NextCursor string            `json:"next_cursor,omitempty"`
+	}
+	res := response{Items: messages}
+
+	if len(messages) > limit {
+		res.Items = messages[:limit]
+		last := res.Items[len(res.Items)-1]
+		res.NextCursor = encodeMessagesCursor(messagesCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	responder.Respond(w, responder.Body(res))
 }
 func (h apiHandler) handleGetRoomMessage(w http.ResponseWriter, r *http.Request) {
 	_, roomID, err := h.getPathID(w, r, "room_id")
@@ -243,17 +505,31 @@ func (h apiHandler) handleGetRoomMessage(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		return
 	}
-	message, err := h.q.GetMessage(r.Context(), messageID)
+	var message pgstore.Message
+	err = traceQuery(r.Context(), "GetMessage", func(ctx context.Context) error {
+		var err error
+		message, err = h.q.GetMessage(ctx, messageID)
+		return err
+	})
+	if err != nil {
+		slog.Error("failed to get message", "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
+		return
+	}
 	if message.RoomID != roomID {
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		responder.RespondError(w, http.StatusNotFound, responder.CodeMessageNotInRoom, "message does not belong to room")
 		return
 	}
 
-	data, _ := json.Marshal(message)
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	responder.Respond(w, responder.Body(message))
 }
 func (h apiHandler) handleCreateRoomMessage(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		respondAuthError(w, ErrUnauthenticated)
+		return
+	}
+
 	rawRoomID, roomID, err := h.getPathID(w, r, "room_id")
 	if err != nil {
 		return
@@ -264,35 +540,132 @@ func (h apiHandler) handleCreateRoomMessage(w http.ResponseWriter, r *http.Reque
 	}
 	var body _body
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidBody, "invalid json")
 		return
 	}
 
-	messageID, err := h.q.InsertMessage(r.Context(), pgstore.InsertMessageParams{RoomID: roomID, Message: body.Message})
+	var messageID uuid.UUID
+	err = traceQuery(r.Context(), "InsertMessage", func(ctx context.Context) error {
+		var err error
+		messageID, err = h.q.InsertMessage(ctx, pgstore.InsertMessageParams{RoomID: roomID, Message: body.Message, AuthorID: user.ID})
+		return err
+	})
 	if err != nil {
 		slog.Error("failed to insert message", "error", err)
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return
 	}
+	metrics.MessagesCreated.Inc()
 
 	type response struct {
 		ID string `json:"id"`
 	}
 
-	data, _ := json.Marshal(response{ID: messageID.String()})
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	responder.Respond(w, responder.Body(response{ID: messageID.String()}))
 
-	go h.notifyClients(Message{
+	go h.notifyClients(r.Context(), Message{
 		Kind:   MessageKindMessageCreated,
 		RoomID: rawRoomID,
 		Value: MessageMessageCreated{
-			ID:      messageID.String(),
-			Message: body.Message,
+			ID:       messageID.String(),
+			Message:  body.Message,
+			AuthorID: user.ID.String(),
 		},
 	})
 }
+func (h apiHandler) handleCreateRoomMessagesBulk(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		respondAuthError(w, ErrUnauthenticated)
+		return
+	}
+
+	rawRoomID, roomID, err := h.getPathID(w, r, "room_id")
+	if err != nil {
+		return
+	}
+
+	type bulkItem struct {
+		Message   string `json:"message"`
+		ClientRef string `json:"client_ref"`
+	}
+	type _body struct {
+		Messages []bulkItem `json:"messages"`
+	}
+	var body _body
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidBody, "invalid json")
+		return
+	}
+	if len(body.Messages) == 0 {
+		responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidBody, "messages must not be empty")
+		return
+	}
+	if len(body.Messages) > maxBulkMessages {
+		responder.RespondError(w, http.StatusBadRequest, responder.CodeInvalidBody, fmt.Sprintf("cannot insert more than %d messages at once", maxBulkMessages))
+		return
+	}
+
+	tx, err := h.pool.Begin(r.Context())
+	if err != nil {
+		slog.Error("failed to begin bulk message transaction", "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
+		return
+	}
+	defer tx.Rollback(r.Context())
+
+	qtx := h.q.WithTx(tx)
+
+	type itemResult struct {
+		ID        string `json:"id"`
+		ClientRef string `json:"client_ref,omitempty"`
+		Status    string `json:"status"`
+	}
+
+	results := make([]itemResult, len(body.Messages))
+	created := make([]MessageMessageCreated, len(body.Messages))
+	for i, item := range body.Messages {
+		var messageID uuid.UUID
+		err := traceQuery(r.Context(), "InsertMessage", func(ctx context.Context) error {
+			var err error
+			messageID, err = qtx.InsertMessage(ctx, pgstore.InsertMessageParams{RoomID: roomID, Message: item.Message, AuthorID: user.ID})
+			return err
+		})
+		if err != nil {
+			slog.Error("failed to insert bulk message", "error", err)
+			responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
+			return
+		}
+
+		results[i] = itemResult{ID: messageID.String(), ClientRef: item.ClientRef, Status: "created"}
+		created[i] = MessageMessageCreated{ID: messageID.String(), Message: item.Message, AuthorID: user.ID.String()}
+	}
+	metrics.MessagesCreated.Add(float64(len(created)))
+
+	if err := tx.Commit(r.Context()); err != nil {
+		slog.Error("failed to commit bulk message transaction", "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
+		return
+	}
+
+	type response struct {
+		Results []itemResult `json:"results"`
+	}
+	responder.Respond(w, responder.Status(http.StatusCreated), responder.Body(response{Results: results}))
+
+	go h.notifyClients(r.Context(), Message{
+		Kind:   MessageKindMessageCreatedBulk,
+		RoomID: rawRoomID,
+		Value:  MessageMessageCreatedBulk{Messages: created},
+	})
+}
 func (h apiHandler) handleReactToMessage(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		respondAuthError(w, ErrUnauthenticated)
+		return
+	}
+
 	rawRoomID, _, err := h.getPathID(w, r, "room_id")
 	if err != nil {
 		return
@@ -301,30 +674,41 @@ func (h apiHandler) handleReactToMessage(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		return
 	}
-	value, err := h.q.ReactToMessage(r.Context(), messageID)
+	var value int64
+	err = traceQuery(r.Context(), "ReactToMessage", func(ctx context.Context) error {
+		var err error
+		value, err = h.q.ReactToMessage(ctx, pgstore.ReactToMessageParams{MessageID: messageID, AuthorID: user.ID})
+		return err
+	})
 	if err != nil {
 		slog.Error("failed to react to message", "error", err)
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return
 	}
+	metrics.MessagesReacted.Inc()
 
 	type response struct {
 		Value int64 `json:"value"`
 	}
-	data, _ := json.Marshal(response{Value: value})
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	responder.Respond(w, responder.Body(response{Value: value}))
 
-	go h.notifyClients(Message{
+	go h.notifyClients(r.Context(), Message{
 		Kind:   MessageKindReactedToMessage,
 		RoomID: rawRoomID,
 		Value: MessageReactedToMessage{
-			ID:    rawMessageID,
-			Value: value,
+			ID:       rawMessageID,
+			Value:    value,
+			AuthorID: user.ID.String(),
 		},
 	})
 }
 func (h apiHandler) handleRemoveReactFromMessage(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		respondAuthError(w, ErrUnauthenticated)
+		return
+	}
+
 	rawRoomID, _, err := h.getPathID(w, r, "room_id")
 	if err != nil {
 		return
@@ -333,21 +717,43 @@ func (h apiHandler) handleRemoveReactFromMessage(w http.ResponseWriter, r *http.
 	if err != nil {
 		return
 	}
-	value, err := h.q.RemoveReactionFromMessage(r.Context(), messageID)
+
+	// Confirm the caller themself reacted to this message before removing
+	// anything: GetReactionAuthor is scoped by (message_id, author_id), so
+	// it can only ever find the caller's own reaction, never someone
+	// else's most recent one.
+	err = traceQuery(r.Context(), "GetReactionAuthor", func(ctx context.Context) error {
+		_, err := h.q.GetReactionAuthor(ctx, pgstore.GetReactionAuthorParams{MessageID: messageID, AuthorID: user.ID})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondAuthError(w, ErrForbidden)
+			return
+		}
+		slog.Error("failed to look up reaction author", "error", err)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
+		return
+	}
+
+	var value int64
+	err = traceQuery(r.Context(), "RemoveReactionFromMessage", func(ctx context.Context) error {
+		var err error
+		value, err = h.q.RemoveReactionFromMessage(ctx, pgstore.RemoveReactionFromMessageParams{MessageID: messageID, AuthorID: user.ID})
+		return err
+	})
 	if err != nil {
 		slog.Error("failed to remove react to message", "error", err)
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return
 	}
 
 	type response struct {
 		Value int64 `json:"value"`
 	}
-	data, _ := json.Marshal(response{Value: value})
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	responder.Respond(w, responder.Body(response{Value: value}))
 
-	go h.notifyClients(Message{
+	go h.notifyClients(r.Context(), Message{
 		Kind:   MessageKindRemovedReactFromMessage,
 		RoomID: rawRoomID,
 		Value: MessageRemovedReactFromMessage{
@@ -357,7 +763,7 @@ func (h apiHandler) handleRemoveReactFromMessage(w http.ResponseWriter, r *http.
 	})
 }
 func (h apiHandler) handleMarkMessageAsAnswered(w http.ResponseWriter, r *http.Request) {
-	rawRoomID, _, err := h.getPathID(w, r, "room_id")
+	rawRoomID, roomID, err := h.getPathID(w, r, "room_id")
 	if err != nil {
 		return
 	}
@@ -365,13 +771,23 @@ func (h apiHandler) handleMarkMessageAsAnswered(w http.ResponseWriter, r *http.R
 	if err != nil {
 		return
 	}
-	if err := h.q.MarkMessagedAsAnswered(r.Context(), messageID); err != nil {
+
+	if err := h.authorize(r.Context(), roomID, PermissionMarkAnswered); err != nil {
+		respondAuthError(w, err)
+		return
+	}
+
+	err = traceQuery(r.Context(), "MarkMessagedAsAnswered", func(ctx context.Context) error {
+		return h.q.MarkMessagedAsAnswered(ctx, messageID)
+	})
+	if err != nil {
 		slog.Error("failed to mark message as answered", "error", err)
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
 		return
 	}
+	metrics.MessagesAnswered.Inc()
 
-	go h.notifyClients(Message{
+	go h.notifyClients(r.Context(), Message{
 		Kind:   MessageKindMarkMessageAsAnswered,
 		RoomID: rawRoomID,
 		Value: MessageMarkMessageAsAnswered{
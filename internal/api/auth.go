@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/responder"
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/store/pgstore"
+)
+
+// User is the authenticated caller, attached to the request context by
+// authMiddleware.
+type User struct {
+	ID   uuid.UUID
+	Name string
+}
+
+type contextKey int
+
+const userContextKey contextKey = iota
+
+func withUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userContextKey, u)
+}
+
+func userFromContext(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userContextKey).(User)
+	return u, ok
+}
+
+type tokenClaims struct {
+	Name string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// authMiddleware validates the JWT bearer token on every /api/** and
+// /subscribe/** request and attaches the resulting User to the request
+// context. Requests without a valid token are rejected before reaching any
+// handler.
+func (h apiHandler) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || rawToken == "" {
+			responder.RespondError(w, http.StatusUnauthorized, responder.CodeUnauthorized, "missing bearer token")
+			return
+		}
+
+		var claims tokenClaims
+		token, err := jwt.ParseWithClaims(rawToken, &claims, func(t *jwt.Token) (any, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			}
+			return h.jwtSecret, nil
+		})
+		if err != nil || !token.Valid {
+			responder.RespondError(w, http.StatusUnauthorized, responder.CodeUnauthorized, "invalid or expired token")
+			return
+		}
+
+		userID, err := uuid.Parse(claims.Subject)
+		if err != nil {
+			responder.RespondError(w, http.StatusUnauthorized, responder.CodeUnauthorized, "invalid token subject")
+			return
+		}
+
+		ctx := withUser(r.Context(), User{ID: userID, Name: claims.Name})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Permission names a room-scoped action gated by authorize.
+type Permission string
+
+// PermissionMarkAnswered is the only permission authorize currently checks.
+// Room creation has no room-scoped permission to check against: any
+// authenticated user may create a room, and doing so is what makes them its
+// room_members owner in the first place (see handleCreateRoom).
+const PermissionMarkAnswered Permission = "mark_answered"
+
+// authError is a typed authorization failure that the caller maps to the
+// right HTTP status and error code.
+type authError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *authError) Error() string { return e.message }
+
+var (
+	ErrUnauthenticated = &authError{status: http.StatusUnauthorized, code: responder.CodeUnauthorized, message: "authentication required"}
+	ErrForbidden       = &authError{status: http.StatusForbidden, code: responder.CodeForbidden, message: "insufficient permissions for this room"}
+)
+
+// respondAuthError writes the right status/code for err when it is one of
+// the sentinel *authError values above; any other error (such as a failed
+// room_members lookup returned by authorize), or nil, gets a 500.
+func respondAuthError(w http.ResponseWriter, err error) {
+	var authErr *authError
+	if errors.As(err, &authErr) {
+		responder.RespondError(w, authErr.status, authErr.code, authErr.message)
+		return
+	}
+	responder.RespondError(w, http.StatusInternalServerError, responder.CodeInternal, "something went wrong")
+}
+
+// authorize checks whether the authenticated user in ctx holds permission
+// in roomID, looking up their role via room_members. Only owners and
+// moderators may mark a message as answered; every other action is left to
+// the caller to check (e.g. reaction removal is gated on authorship, not
+// role).
+func (h apiHandler) authorize(ctx context.Context, roomID uuid.UUID, permission Permission) error {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	var member pgstore.RoomMember
+	err := traceQuery(ctx, "GetRoomMember", func(ctx context.Context) error {
+		var err error
+		member, err = h.q.GetRoomMember(ctx, pgstore.GetRoomMemberParams{RoomID: roomID, UserID: user.ID})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrForbidden
+		}
+		return err
+	}
+
+	switch permission {
+	case PermissionMarkAnswered:
+		if member.Role == pgstore.RoomMemberRoleOwner || member.Role == pgstore.RoomMemberRoleModerator {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
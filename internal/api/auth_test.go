@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/store/pgstore"
+)
+
+// fakeRow implements pgx.Row around a canned Scan, so authorize can be
+// exercised against a stubbed GetRoomMember without a real database.
+type fakeRow struct {
+	scan func(dest ...any) error
+}
+
+func (r fakeRow) Scan(dest ...any) error { return r.scan(dest...) }
+
+// fakeDBTX implements pgstore.DBTX, answering every QueryRow with row.
+type fakeDBTX struct {
+	row pgx.Row
+}
+
+func (d fakeDBTX) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (d fakeDBTX) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, nil
+}
+
+func (d fakeDBTX) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return d.row
+}
+
+func memberRow(role pgstore.RoomMemberRole) pgx.Row {
+	return fakeRow{scan: func(dest ...any) error {
+		*dest[0].(*uuid.UUID) = uuid.New()
+		*dest[1].(*uuid.UUID) = uuid.New()
+		*dest[2].(*pgstore.RoomMemberRole) = role
+		return nil
+	}}
+}
+
+func notFoundRow() pgx.Row {
+	return fakeRow{scan: func(dest ...any) error { return pgx.ErrNoRows }}
+}
+
+func TestAuthorizeMarkAnswered(t *testing.T) {
+	roomID := uuid.New()
+	userID := uuid.New()
+
+	cases := []struct {
+		name    string
+		row     pgx.Row
+		wantErr error
+	}{
+		{"owner can mark answered", memberRow(pgstore.RoomMemberRoleOwner), nil},
+		{"moderator can mark answered", memberRow(pgstore.RoomMemberRoleModerator), nil},
+		{"participant cannot mark answered", memberRow(pgstore.RoomMemberRoleParticipant), ErrForbidden},
+		{"non-member is forbidden", notFoundRow(), ErrForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := apiHandler{q: pgstore.New(fakeDBTX{row: tc.row})}
+			ctx := withUser(context.Background(), User{ID: userID})
+
+			err := h.authorize(ctx, roomID, PermissionMarkAnswered)
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Fatalf("authorize() = %v, want nil", err)
+				}
+				return
+			}
+			if err != tc.wantErr {
+				t.Fatalf("authorize() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthorizeRequiresAuthenticatedUser(t *testing.T) {
+	h := apiHandler{}
+	if err := h.authorize(context.Background(), uuid.New(), PermissionMarkAnswered); err != ErrUnauthenticated {
+		t.Fatalf("authorize() = %v, want ErrUnauthenticated", err)
+	}
+}
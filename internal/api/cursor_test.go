@@ -0,0 +1,38 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMessagesCursorRoundTrip(t *testing.T) {
+	want := messagesCursor{
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	got, err := decodeMessagesCursor(encodeMessagesCursor(want))
+	if err != nil {
+		t.Fatalf("decodeMessagesCursor: %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Fatalf("decodeMessagesCursor roundtrip = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeMessagesCursorRejectsMalformedInput(t *testing.T) {
+	cases := []string{
+		"not-base64!!!",
+		"",
+		// valid base64, but missing the "|" separator
+		"bm8tc2VwYXJhdG9y",
+	}
+
+	for _, raw := range cases {
+		if _, err := decodeMessagesCursor(raw); err == nil {
+			t.Errorf("decodeMessagesCursor(%q) = nil error, want error", raw)
+		}
+	}
+}
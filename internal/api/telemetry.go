@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/metrics"
+)
+
+var tracer = otel.Tracer("github.com/Kitsai/Rocketseat-Go-React-Back/internal/api")
+
+// tracingMiddleware starts a server span per HTTP request, extracting the
+// W3C traceparent header (if any) so requests chain onto an upstream trace.
+func (h apiHandler) tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// metricsMiddleware records handler latency per route pattern (e.g.
+// "/api/rooms/{room_id}/messages") rather than per concrete path, so the
+// histogram's label cardinality stays bounded. Requests that match no route
+// share the single "unmatched" label.
+func (h apiHandler) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		metrics.HandlerDuration.WithLabelValues(route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// traceQuery runs fn inside a child span tagged as a postgres call via
+// pgstore, and records its duration in the DB query duration histogram.
+// name should match the pgstore.Queries method being called, e.g.
+// "GetRoomMessagesPage".
+func traceQuery(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+
+	ctx, span := tracer.Start(ctx, "pgstore."+name, trace.WithAttributes(
+		attribute.String("db.system", "postgres"),
+		attribute.String("db.statement", name),
+	))
+	defer span.End()
+
+	err := fn(ctx)
+
+	metrics.DBQueryDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package hub
+
+import (
+	"context"
+	"sync"
+)
+
+// Broker fans messages for a room out to every API instance that has a
+// subscriber for it. The Hub publishes locally-originated messages through
+// a Broker and relays whatever the Broker delivers back to its own
+// connections, so multiple instances can share subscribers for the same
+// room.
+type Broker interface {
+	// Publish sends payload to every subscriber of room, on this instance
+	// and any other.
+	Publish(ctx context.Context, room string, payload []byte) error
+
+	// Subscribe starts listening for messages published to room. It returns
+	// a channel of incoming payloads and an unsubscribe func that must be
+	// called to release the subscription.
+	Subscribe(ctx context.Context, room string) (<-chan []byte, func(), error)
+}
+
+// InMemoryBroker is the default Broker: it fans messages out to local
+// subscribers only, with no cross-instance delivery. It is suitable for a
+// single API instance.
+type InMemoryBroker struct {
+	mu   sync.Mutex
+	subs map[string]map[chan []byte]struct{}
+}
+
+// NewInMemoryBroker returns a ready-to-use InMemoryBroker.
+func NewInMemoryBroker() *InMemoryBroker {
+	return &InMemoryBroker{subs: make(map[string]map[chan []byte]struct{})}
+}
+
+func (b *InMemoryBroker) Publish(_ context.Context, room string, payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[room] {
+		select {
+		case ch <- payload:
+		default:
+			// Subscriber isn't keeping up; drop the message rather than
+			// block the publisher.
+		}
+	}
+	return nil
+}
+
+func (b *InMemoryBroker) Subscribe(_ context.Context, room string) (<-chan []byte, func(), error) {
+	ch := make(chan []byte, 16)
+
+	b.mu.Lock()
+	if b.subs[room] == nil {
+		b.subs[room] = make(map[chan []byte]struct{})
+	}
+	b.subs[room][ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs[room], ch)
+		if len(b.subs[room]) == 0 {
+			delete(b.subs, room)
+		}
+		close(ch)
+	}
+
+	return ch, unsubscribe, nil
+}
@@ -0,0 +1,318 @@
+// Package hub implements a websocket fan-out subsystem modeled on gorilla's
+// chat example: every connection gets a reader and a writer goroutine, the
+// writer owns WriteMessage and is the only goroutine that ever touches the
+// connection for writes, and a central Hub goroutine owns room membership
+// so it never needs a lock shared with the read/write goroutines.
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Kitsai/Rocketseat-Go-React-Back/internal/metrics"
+)
+
+const (
+	// sendBuffer is how many pending messages a slow client can queue
+	// before it's disconnected instead of blocking the rest of the room.
+	sendBuffer = 16
+
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 32 * 1024
+)
+
+// Client is a single subscriber connection, registered to exactly one room.
+type Client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	room   string
+	send   chan []byte
+	onRead func(c *Client, raw []byte)
+}
+
+// Hub owns room membership and fans broadcast messages out to every
+// connection registered for a room. A Hub must be created with New.
+type Hub struct {
+	broker Broker
+
+	register       chan *Client
+	unregister     chan *Client
+	broadcast      chan roomPayload
+	localBroadcast chan localPayload
+	countQuery     chan countQuery
+
+	rooms map[string]map[*Client]bool
+
+	subCancel    map[string]func()
+	subRoomCount map[string]int
+}
+
+type roomPayload struct {
+	room    string
+	payload []byte
+}
+
+// localPayload is a broadcast that never goes through the Broker: it only
+// reaches connections held by this instance, and can skip the client that
+// triggered it. Used for ephemeral events (typing, presence) that aren't
+// worth persisting or replicating across instances.
+type localPayload struct {
+	room    string
+	payload []byte
+	except  *Client
+}
+
+// countQuery asks run() for the number of local subscribers of a room.
+type countQuery struct {
+	room string
+	resp chan int
+}
+
+// New returns a Hub using broker for cross-instance fan-out and starts its
+// run loop. Pass NewInMemoryBroker() for a single-instance deployment.
+func New(broker Broker) *Hub {
+	h := &Hub{
+		broker:         broker,
+		register:       make(chan *Client),
+		unregister:     make(chan *Client),
+		broadcast:      make(chan roomPayload),
+		localBroadcast: make(chan localPayload),
+		countQuery:     make(chan countQuery),
+		rooms:          make(map[string]map[*Client]bool),
+		subCancel:      make(map[string]func()),
+		subRoomCount:   make(map[string]int),
+	}
+	go h.run()
+	return h
+}
+
+func (h *Hub) run() {
+	for {
+		select {
+		case c := <-h.register:
+			if h.rooms[c.room] == nil {
+				h.rooms[c.room] = make(map[*Client]bool)
+			}
+			h.rooms[c.room][c] = true
+			h.subRoomCount[c.room]++
+			metrics.ActiveSubscribers.Inc()
+			if h.subRoomCount[c.room] == 1 {
+				h.subscribeRoom(c.room)
+			}
+
+		case c := <-h.unregister:
+			h.removeClient(c)
+
+		case rp := <-h.broadcast:
+			for c := range h.rooms[rp.room] {
+				h.send(rp.room, c, rp.payload)
+			}
+
+		case lp := <-h.localBroadcast:
+			for c := range h.rooms[lp.room] {
+				if c == lp.except {
+					continue
+				}
+				h.send(lp.room, c, lp.payload)
+			}
+
+		case q := <-h.countQuery:
+			q.resp <- len(h.rooms[q.room])
+		}
+	}
+}
+
+// send delivers payload to c's buffered channel, dropping c from room if it
+// isn't keeping up rather than blocking the rest of the room.
+func (h *Hub) send(room string, c *Client, payload []byte) {
+	select {
+	case c.send <- payload:
+	default:
+		slog.Warn("disconnecting slow websocket client", "room_id", room)
+		h.removeClient(c)
+	}
+}
+
+// removeClient drops c from its room and retires the room entirely once its
+// last local subscriber leaves, cancelling the broker subscription started
+// by subscribeRoom and the gauge it bumped. Called from both the unregister
+// case (client-initiated disconnect) and send (slow-client drop), so
+// neither path can leak a room's bookkeeping.
+func (h *Hub) removeClient(c *Client) {
+	if _, ok := h.rooms[c.room][c]; ok {
+		delete(h.rooms[c.room], c)
+		close(c.send)
+		metrics.ActiveSubscribers.Dec()
+		h.subRoomCount[c.room]--
+		if h.subRoomCount[c.room] <= 0 {
+			delete(h.rooms, c.room)
+			delete(h.subRoomCount, c.room)
+			if cancel, ok := h.subCancel[c.room]; ok {
+				cancel()
+				delete(h.subCancel, c.room)
+			}
+		}
+	}
+}
+
+// subscribeRoom starts relaying whatever the broker delivers for room into
+// this Hub's local broadcast channel. Called from run(), so it only ever
+// runs for the first local subscriber of a room.
+func (h *Hub) subscribeRoom(room string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.subCancel[room] = cancel
+
+	incoming, unsubscribe, err := h.broker.Subscribe(ctx, room)
+	if err != nil {
+		slog.Error("failed to subscribe to broker", "room_id", room, "error", err)
+		return
+	}
+
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case payload, ok := <-incoming:
+				if !ok {
+					return
+				}
+				h.broadcast <- roomPayload{room: room, payload: payload}
+			}
+		}
+	}()
+}
+
+// Broadcast publishes msg to every subscriber of room, local or remote.
+func (h *Hub) Broadcast(room string, msg any) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("failed to marshal broadcast message", "error", err)
+		return
+	}
+	if err := h.broker.Publish(context.Background(), room, payload); err != nil {
+		slog.Error("failed to publish broadcast message", "error", err)
+	}
+}
+
+// BroadcastLocalExcept publishes msg to every local subscriber of room
+// except except, without going through the Broker. Intended for ephemeral,
+// unpersisted events like typing and presence.
+func (h *Hub) BroadcastLocalExcept(room string, msg any, except *Client) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("failed to marshal local broadcast message", "error", err)
+		return
+	}
+	h.localBroadcast <- localPayload{room: room, payload: payload, except: except}
+}
+
+// LocalSubscriberCount returns how many connections this instance currently
+// holds for room. It does not include subscribers of the same room on other
+// instances.
+func (h *Hub) LocalSubscriberCount(room string) int {
+	resp := make(chan int, 1)
+	h.countQuery <- countQuery{room: room, resp: resp}
+	return <-resp
+}
+
+// Register joins conn to room and returns the resulting Client. Once this
+// call returns, c is a live subscriber: any message broadcast to room is
+// already queued onto c's send channel, so a caller computing a since-cursor
+// replay should do so only *after* Register returns, to avoid a gap where a
+// message published between the cursor read and subscribing is neither
+// replayed nor delivered live. Call Serve on the returned Client to start
+// relaying messages and block until the connection closes.
+func (h *Hub) Register(conn *websocket.Conn, room string, onRead func(c *Client, raw []byte)) *Client {
+	c := &Client{
+		hub:    h,
+		conn:   conn,
+		room:   room,
+		send:   make(chan []byte, sendBuffer),
+		onRead: onRead,
+	}
+
+	h.register <- c
+	return c
+}
+
+// Serve starts c's reader and writer goroutines and blocks until the
+// connection is closed. replay, if given, is pushed onto c's send channel
+// after whatever was broadcast to the room between Register and Serve, so
+// those live messages are delivered before the replay, and a message
+// published after Register can arrive both live and in the replay. The
+// writer goroutine is started first so pushing a replay longer than
+// sendBuffer can't deadlock against live broadcasts arriving concurrently.
+func (c *Client) Serve(replay ...[]byte) {
+	done := make(chan struct{})
+	go func() {
+		c.writePump()
+		close(done)
+	}()
+
+	for _, payload := range replay {
+		c.send <- payload
+	}
+
+	c.readPump()
+	<-done
+}
+
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				slog.Warn("websocket write error", "room_id", c.room, "error", err)
+				return
+			}
+
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				slog.Warn("websocket ping error", "room_id", c.room, "error", err)
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unregister <- c
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, raw, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				slog.Warn("websocket read error", "room_id", c.room, "error", err)
+			}
+			return
+		}
+		if c.onRead != nil {
+			c.onRead(c, raw)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package hub
+
+import "testing"
+
+// TestSendDropsSlowClientAndCleansUpBookkeeping guards against the
+// slow-client drop path reintroducing the bookkeeping leak fixed by
+// removeClient: dropping a client outside of the unregister case must still
+// decrement subRoomCount/ActiveSubscribers and cancel the room's broker
+// subscription once its last local subscriber is gone.
+func TestSendDropsSlowClientAndCleansUpBookkeeping(t *testing.T) {
+	h := New(NewInMemoryBroker())
+
+	c := &Client{hub: h, room: "room1", send: make(chan []byte, 1)}
+	h.register <- c
+
+	if got := h.LocalSubscriberCount("room1"); got != 1 {
+		t.Fatalf("LocalSubscriberCount = %d, want 1", got)
+	}
+
+	// c never drains its channel, so filling the buffer and sending once
+	// more should disconnect it as a slow client.
+	h.send("room1", c, []byte("first"))
+	h.send("room1", c, []byte("second"))
+
+	if got := h.LocalSubscriberCount("room1"); got != 0 {
+		t.Fatalf("LocalSubscriberCount after slow-client drop = %d, want 0", got)
+	}
+
+	if _, ok := h.subCancel["room1"]; ok {
+		t.Fatal("subCancel entry for room1 was not cleaned up after its last subscriber was dropped")
+	}
+}
@@ -0,0 +1,69 @@
+package hub
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBroker is a Broker backed by Redis Pub/Sub, letting several API
+// instances share subscribers for the same room: each instance publishes
+// to Redis instead of writing directly to connections, and every
+// instance's Hub delivers whatever Redis fans back to its own local
+// clients.
+type RedisBroker struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisBroker returns a Broker that publishes and subscribes through the
+// given Redis client. channel keys are namespaced under prefix (e.g.
+// "room:") to avoid colliding with unrelated Pub/Sub traffic.
+func NewRedisBroker(client *redis.Client, prefix string) *RedisBroker {
+	return &RedisBroker{client: client, prefix: prefix}
+}
+
+func (b *RedisBroker) channel(room string) string {
+	return b.prefix + room
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, room string, payload []byte) error {
+	return b.client.Publish(ctx, b.channel(room), payload).Err()
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, room string) (<-chan []byte, func(), error) {
+	pubsub := b.client.Subscribe(ctx, b.channel(room))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, nil, err
+	}
+
+	out := make(chan []byte, 16)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(out)
+		msgs := pubsub.Channel()
+		for {
+			select {
+			case <-done:
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- []byte(msg.Payload):
+				default:
+				}
+			}
+		}
+	}()
+
+	unsubscribe := func() {
+		close(done)
+		_ = pubsub.Close()
+	}
+
+	return out, unsubscribe, nil
+}
@@ -0,0 +1,49 @@
+// Package metrics holds the Prometheus collectors shared across internal/api
+// and internal/hub, so handler and hub code only need to call Inc/Observe
+// without wiring up registration themselves.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// MessagesCreated, MessagesReacted, MessagesAnswered, and
+	// ActiveSubscribers are intentionally unlabeled by room: rooms are
+	// user-created UUIDs with no bound on how many exist, and labeling a
+	// collector by them would give Prometheus unbounded cardinality that
+	// leaks memory in the collector for the life of the process. Room-level
+	// counts belong in spans/logs (see notifyClients), not metrics.
+	MessagesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "wsrs_messages_created_total",
+		Help: "Number of messages created.",
+	})
+
+	MessagesReacted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "wsrs_messages_reacted_total",
+		Help: "Number of reactions added to messages.",
+	})
+
+	MessagesAnswered = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "wsrs_messages_answered_total",
+		Help: "Number of messages marked as answered.",
+	})
+
+	ActiveSubscribers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "wsrs_active_subscribers",
+		Help: "Current number of websocket subscribers, across all rooms.",
+	})
+
+	HandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wsrs_handler_duration_seconds",
+		Help:    "HTTP handler latency, per route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wsrs_db_query_duration_seconds",
+		Help:    "pgstore query duration, per query name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"query"})
+)
@@ -0,0 +1,16 @@
+package responder
+
+// Stable error codes used across internal/api. These are part of the public
+// contract with the frontend, so existing values must not change meaning
+// once shipped.
+const (
+	CodeInvalidBody      = "INVALID_BODY"
+	CodeInvalidUUID      = "INVALID_UUID"
+	CodeRoomNotFound     = "ROOM_NOT_FOUND"
+	CodeMessageNotFound  = "MESSAGE_NOT_FOUND"
+	CodeMessageNotInRoom = "MESSAGE_NOT_IN_ROOM"
+	CodeUpgradeFailed    = "UPGRADE_FAILED"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeInternal         = "INTERNAL_ERROR"
+)
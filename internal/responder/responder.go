@@ -0,0 +1,72 @@
+// Package responder centralizes how handlers write JSON back to the client,
+// so every endpoint in internal/api serializes success and error bodies the
+// same way.
+package responder
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// Option configures a single call to Respond.
+type Option func(*response)
+
+type response struct {
+	status int
+	body   any
+	pretty bool
+}
+
+// Status sets the HTTP status code written with the response. Defaults to
+// http.StatusOK when not provided.
+func Status(code int) Option {
+	return func(r *response) { r.status = code }
+}
+
+// Body sets the value to be marshaled as the JSON response body.
+func Body(v any) Option {
+	return func(r *response) { r.body = v }
+}
+
+// Pretty indents the marshaled JSON, useful for debugging endpoints.
+func Pretty() Option {
+	return func(r *response) { r.pretty = true }
+}
+
+// Respond writes a JSON response to w according to the given options.
+func Respond(w http.ResponseWriter, opts ...Option) {
+	res := response{status: http.StatusOK}
+	for _, opt := range opts {
+		opt(&res)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(res.status)
+
+	if res.body == nil {
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	if res.pretty {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(res.body); err != nil {
+		slog.Error("failed to encode response body", "error", err)
+	}
+}
+
+// Error is the typed envelope returned for every failed request, so the
+// frontend can branch on Code instead of parsing Message prose.
+type Error struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RespondError writes an Error envelope with the given status, stable code
+// and human-readable message.
+func RespondError(w http.ResponseWriter, status int, code, message string) {
+	Respond(w, Status(status), Body(Error{Status: status, Code: code, Message: message}))
+}